@@ -0,0 +1,228 @@
+// Package agent implements the NanoGrid example function: it sums a range
+// of integers, reports the average, and persists the result as JSON.
+package agent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"runtime"
+	"time"
+)
+
+// phase names used as the slog message for each step of a Run.
+const (
+	phaseStart   = "start"
+	phaseEnv     = "env"
+	phaseCompute = "compute"
+	phaseWrite   = "write"
+	phaseDone    = "done"
+)
+
+// Result is the document persisted after a successful run.
+type Result struct {
+	Timestamp string `json:"timestamp"`
+	GoVersion string `json:"goVersion"`
+	// Sum is the exact integer sum for the synthetic range path. It is
+	// only set when that path ran; see SumFloat for the HTTP source path.
+	Sum int64 `json:"sum,omitempty"`
+	// SumFloat is the sum for the HTTP time series path, where values are
+	// real-valued (e.g. closing prices) and an int64 can't represent them
+	// exactly. Keeping this separate from Sum means the integer range
+	// path never loses precision by round-tripping through float64.
+	SumFloat float64 `json:"sumFloat,omitempty"`
+	Average  float64 `json:"average"`
+	Message  string  `json:"message"`
+	// Rev is set by sinks that version documents (e.g. CouchDBSink) and is
+	// otherwise left empty.
+	Rev string `json:"rev,omitempty"`
+}
+
+// config holds the resolved settings for a Run, built from the defaults
+// below and then overridden by whatever Options the caller supplies.
+type config struct {
+	start      int
+	end        int
+	outputPath string
+	message    string
+	clock      func() time.Time
+	writer     io.Writer
+	sinks      []ResultSink
+	workers    int
+	partFactor int
+	httpSource string
+	httpField  string
+	httpClient *http.Client
+}
+
+func defaultConfig() config {
+	return config{
+		start:      1,
+		end:        1000,
+		outputPath: "output.json",
+		message:    "Function executed successfully!",
+		clock:      time.Now,
+		writer:     os.Stderr,
+		workers:    runtime.GOMAXPROCS(0),
+		partFactor: defaultPartitionFactor,
+		httpClient: http.DefaultClient,
+	}
+}
+
+// Option customizes a Run.
+type Option func(*config)
+
+// WithRange sets the inclusive integer range to sum over. The default is
+// 1..1000, matching the original hard-coded behavior.
+func WithRange(start, end int) Option {
+	return func(c *config) {
+		c.start = start
+		c.end = end
+	}
+}
+
+// WithOutputPath sets the path the Result JSON is written to. The default
+// is "output.json".
+func WithOutputPath(path string) Option {
+	return func(c *config) {
+		c.outputPath = path
+	}
+}
+
+// WithMessage overrides the Message field recorded in the Result.
+func WithMessage(message string) Option {
+	return func(c *config) {
+		c.message = message
+	}
+}
+
+// WithClock overrides the time source used for Result.Timestamp, so tests
+// can produce deterministic output.
+func WithClock(clock func() time.Time) Option {
+	return func(c *config) {
+		c.clock = clock
+	}
+}
+
+// WithWriter overrides where structured log telemetry is written. The
+// default is os.Stderr.
+func WithWriter(w io.Writer) Option {
+	return func(c *config) {
+		c.writer = w
+	}
+}
+
+// WithWorkers sets how many goroutines the compute stage uses. The default
+// is runtime.GOMAXPROCS(0).
+func WithWorkers(workers int) Option {
+	return func(c *config) {
+		c.workers = workers
+	}
+}
+
+// WithPartitionFactor sets how many partitions are created per worker. The
+// default is 10, which keeps workers busy even when partitions finish at
+// uneven rates.
+func WithPartitionFactor(factor int) Option {
+	return func(c *config) {
+		c.partFactor = factor
+	}
+}
+
+// WithHTTPSource switches Run from the synthetic integer range to fetching
+// a time series Envelope from url and summing the named field (one of
+// "open", "high", "low", "close" or "volume") across all of its points.
+func WithHTTPSource(url, field string) Option {
+	return func(c *config) {
+		c.httpSource = url
+		c.httpField = field
+	}
+}
+
+// WithSink attaches a ResultSink the Result is persisted to. It can be
+// supplied multiple times to persist to several sinks in one Run; if it is
+// never supplied, Run defaults to a single FileSink at the configured
+// output path.
+func WithSink(sink ResultSink) Option {
+	return func(c *config) {
+		c.sinks = append(c.sinks, sink)
+	}
+}
+
+// Run performs the computation and persists the Result, returning it so
+// callers can inspect or further process it instead of re-reading the
+// output file.
+func Run(opts ...Option) (Result, error) {
+	cfg := defaultConfig()
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	log := newLogger(cfg.writer)
+	started := time.Now()
+	log.Info(phaseStart)
+
+	wd, _ := os.Getwd()
+	log.Info(phaseEnv,
+		"go_version", runtime.Version(),
+		"os", runtime.GOOS,
+		"arch", runtime.GOARCH,
+		"cwd", wd,
+	)
+
+	result := Result{
+		Timestamp: cfg.clock().Format(time.RFC3339),
+		GoVersion: runtime.Version(),
+		Message:   cfg.message,
+	}
+
+	var count int64
+	if cfg.httpSource != "" {
+		n, sumFloat, err := computeFromHTTPSource(context.Background(), cfg.httpClient, cfg.httpSource, cfg.httpField)
+		if err != nil {
+			err = fmt.Errorf("computing from http source: %w", err)
+			log.Error(phaseCompute, "err", err)
+			return Result{}, err
+		}
+		count = n
+		result.SumFloat = sumFloat
+		if count > 0 {
+			result.Average = sumFloat / float64(count)
+		}
+		log.Info(phaseCompute, "count", count, "sum", sumFloat, "average", result.Average)
+	} else {
+		var sum int64
+		count, sum = computeRange(cfg.start, cfg.end, cfg.workers, cfg.partFactor)
+		result.Sum = sum
+		if count > 0 {
+			result.Average = float64(sum) / float64(count)
+		}
+		log.Info(phaseCompute, "count", count, "sum", sum, "average", result.Average)
+	}
+
+	sinks := cfg.sinks
+	if len(sinks) == 0 {
+		sinks = []ResultSink{FileSink{Path: cfg.outputPath}}
+	}
+
+	var putErrs []error
+	for _, sink := range sinks {
+		if err := sink.Put(context.Background(), &result); err != nil {
+			log.Error(phaseWrite, "err", err)
+			putErrs = append(putErrs, err)
+			continue
+		}
+	}
+	log.Info(phaseWrite, "output_path", cfg.outputPath)
+
+	if err := errors.Join(putErrs...); err != nil {
+		return result, fmt.Errorf("persisting result: %w", err)
+	}
+
+	log.Info(phaseDone, "duration_ms", time.Since(started).Milliseconds())
+
+	return result, nil
+}
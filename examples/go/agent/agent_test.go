@@ -0,0 +1,40 @@
+package agent
+
+import (
+	"io"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// TestRunKeepsIntegerSumExact guards against the precision regression where
+// Result.Sum for the synthetic range path was round-tripped through
+// float64 before being stored. 9007199254740993 is one past 2^53, beyond
+// which float64 can no longer represent every integer exactly, so a lossy
+// path would silently round it down to 9007199254740992.
+func TestRunKeepsIntegerSumExact(t *testing.T) {
+	const n = int64(1) << 53 // 2^53
+	const exact = n + 1      // not exactly representable as float64
+
+	if int64(float64(exact)) == exact {
+		t.Fatalf("test setup invalid: %d is representable as float64", exact)
+	}
+
+	outputPath := filepath.Join(t.TempDir(), "output.json")
+	result, err := Run(
+		WithRange(int(exact), int(exact)),
+		WithOutputPath(outputPath),
+		WithWriter(io.Discard),
+		WithClock(func() time.Time { return time.Unix(0, 0) }),
+	)
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+
+	if result.Sum != exact {
+		t.Fatalf("result.Sum = %d, want %d (exact, not round-tripped through float64)", result.Sum, exact)
+	}
+	if result.SumFloat != 0 {
+		t.Fatalf("result.SumFloat = %v, want 0 for the integer range path", result.SumFloat)
+	}
+}
@@ -0,0 +1,84 @@
+package agent
+
+import "runtime"
+
+// defaultPartitionFactor is the default number of partitions created per
+// worker; a factor greater than 1 keeps workers busy even when partitions
+// finish at uneven rates.
+const defaultPartitionFactor = 10
+
+// partition is a contiguous, inclusive sub-range of the range being summed.
+type partition struct {
+	start, end int64
+}
+
+// partial is one worker's contribution before the partitions are reduced.
+type partial struct {
+	count int64
+	sum   int64
+}
+
+// computeRange sums the inclusive integer range [start, end] using a pool
+// of workers goroutines, each consuming contiguous partitions from a
+// buffered channel. The range is split into at most workers*partitionFactor
+// equal-sized partitions (fewer when the range is short, and the last one may
+// be smaller) so workers stay busy even if some partitions are cheaper than
+// others. Accumulation happens in int64 to avoid overflow on large ranges;
+// the result matches summing the range sequentially.
+func computeRange(start, end, workers, partitionFactor int) (count int64, sum int64) {
+	if end < start {
+		return 0, 0
+	}
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	if partitionFactor <= 0 {
+		partitionFactor = defaultPartitionFactor
+	}
+
+	total := int64(end) - int64(start) + 1
+
+	numPartitions := int64(workers) * int64(partitionFactor)
+	if numPartitions > total {
+		numPartitions = total
+	}
+	if numPartitions < 1 {
+		numPartitions = 1
+	}
+	partitionSize := (total + numPartitions - 1) / numPartitions
+
+	jobs := make(chan partition, numPartitions)
+	results := make(chan partial, numPartitions)
+
+	for w := 0; w < workers; w++ {
+		go func() {
+			for p := range jobs {
+				var c, s int64
+				for n := p.start; n <= p.end; n++ {
+					s += n
+					c++
+				}
+				results <- partial{count: c, sum: s}
+			}
+		}()
+	}
+
+	var numJobs int64
+	for s := int64(start); s <= int64(end); s += partitionSize {
+		e := s + partitionSize - 1
+		if e > int64(end) {
+			e = int64(end)
+		}
+		jobs <- partition{start: s, end: e}
+		numJobs++
+	}
+	close(jobs)
+
+	for i := int64(0); i < numJobs; i++ {
+		p := <-results
+		count += p.count
+		sum += p.sum
+	}
+
+	return count, sum
+}
@@ -0,0 +1,62 @@
+package agent
+
+import (
+	"io"
+	"math/rand"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// sequentialSum is the reference implementation computeRange is checked
+// against: a plain sequential loop over the same inclusive range.
+func sequentialSum(start, end int) (count int64, sum int64) {
+	for n := int64(start); n <= int64(end); n++ {
+		sum += n
+		count++
+	}
+	return count, sum
+}
+
+func TestComputeRangeMatchesSequential(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+
+	for i := 0; i < 50; i++ {
+		start := rng.Intn(1000) - 500
+		end := start + rng.Intn(5000)
+		workers := 1 + rng.Intn(8)
+		partitionFactor := 1 + rng.Intn(20)
+
+		wantCount, wantSum := sequentialSum(start, end)
+		gotCount, gotSum := computeRange(start, end, workers, partitionFactor)
+
+		if gotCount != wantCount || gotSum != wantSum {
+			t.Fatalf("computeRange(%d, %d, workers=%d, factor=%d) = (%d, %d), want (%d, %d)",
+				start, end, workers, partitionFactor, gotCount, gotSum, wantCount, wantSum)
+		}
+	}
+}
+
+func TestComputeRangeEmptyRange(t *testing.T) {
+	count, sum := computeRange(10, 5, 4, 10)
+	if count != 0 || sum != 0 {
+		t.Fatalf("computeRange on an empty range = (%d, %d), want (0, 0)", count, sum)
+	}
+}
+
+func BenchmarkRun_1e8(b *testing.B) {
+	outputPath := filepath.Join(b.TempDir(), "output.json")
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		_, err := Run(
+			WithRange(1, 100_000_000),
+			WithOutputPath(outputPath),
+			WithWriter(io.Discard),
+			WithClock(func() time.Time { return time.Unix(0, 0) }),
+		)
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,38 @@
+package agent
+
+import (
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+)
+
+// newLogger builds the slog.Logger telemetry is emitted through. The level
+// and format are controlled by AGENT_LOG_LEVEL and AGENT_LOG_FORMAT so the
+// NanoGrid orchestrator can consume JSON while humans can switch to text
+// locally.
+func newLogger(w io.Writer) *slog.Logger {
+	opts := &slog.HandlerOptions{Level: logLevelFromEnv()}
+
+	var handler slog.Handler
+	if strings.EqualFold(os.Getenv("AGENT_LOG_FORMAT"), "text") {
+		handler = slog.NewTextHandler(w, opts)
+	} else {
+		handler = slog.NewJSONHandler(w, opts)
+	}
+
+	return slog.New(handler)
+}
+
+func logLevelFromEnv() slog.Level {
+	switch strings.ToLower(os.Getenv("AGENT_LOG_LEVEL")) {
+	case "debug":
+		return slog.LevelDebug
+	case "warn":
+		return slog.LevelWarn
+	case "error":
+		return slog.LevelError
+	default:
+		return slog.LevelInfo
+	}
+}
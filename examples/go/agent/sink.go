@@ -0,0 +1,147 @@
+package agent
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/Softbank-Final/NanoGrid-Agent-PY/examples/go/jsoncodec"
+)
+
+// ResultSink persists a Result somewhere. Multiple sinks can be attached to
+// a single Run; a failure in one must not stop the others from running.
+type ResultSink interface {
+	Put(ctx context.Context, result *Result) error
+}
+
+// FileSink writes the Result as indented JSON to a local path. It is the
+// default sink, matching the agent's original on-disk behavior.
+type FileSink struct {
+	Path string
+}
+
+// Put implements ResultSink.
+func (s FileSink) Put(ctx context.Context, result *Result) error {
+	data, err := jsoncodec.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshaling result: %w", err)
+	}
+	if err := os.WriteFile(s.Path, data, 0644); err != nil {
+		return fmt.Errorf("writing output file: %w", err)
+	}
+	return nil
+}
+
+// couchDocMaxAttempts bounds how many PUT attempts CouchDBSink makes, each
+// under a freshly derived document ID, before giving up on 409 conflicts.
+const couchDocMaxAttempts = 3
+
+// CouchDBSink PUTs the Result to a CouchDB database as a JSON document under
+// a generated document ID, surfacing the assigned revision back into Result.Rev.
+type CouchDBSink struct {
+	// URL is the CouchDB server base URL, e.g. "http://localhost:5984".
+	URL string
+	// DB is the target database name.
+	DB string
+	// Username and Password enable HTTP basic auth when both are set.
+	Username string
+	Password string
+	// Client is the HTTP client used to reach CouchDB. A zero value uses
+	// http.DefaultClient.
+	Client *http.Client
+}
+
+type couchPutResponse struct {
+	OK  bool   `json:"ok"`
+	ID  string `json:"id"`
+	Rev string `json:"rev"`
+}
+
+type couchDoc struct {
+	ID string `json:"_id"`
+	Result
+}
+
+// Put implements ResultSink. It retries with a freshly derived document ID
+// on 409 conflicts.
+func (s CouchDBSink) Put(ctx context.Context, result *Result) error {
+	client := s.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	var lastErr error
+	for attempt := 0; attempt < couchDocMaxAttempts; attempt++ {
+		docID := s.docID(*result, attempt)
+		body, err := jsoncodec.Marshal(couchDoc{ID: docID, Result: *result})
+		if err != nil {
+			return fmt.Errorf("marshaling couchdb document: %w", err)
+		}
+
+		url := strings.TrimRight(s.URL, "/") + "/" + s.DB + "/" + docID
+		req, err := http.NewRequestWithContext(ctx, http.MethodPut, url, bytes.NewReader(body))
+		if err != nil {
+			return fmt.Errorf("building couchdb request: %w", err)
+		}
+		req.Header.Set("Content-Type", "application/json")
+		if s.Username != "" && s.Password != "" {
+			req.SetBasicAuth(s.Username, s.Password)
+		}
+
+		resp, err := client.Do(req)
+		if err != nil {
+			return fmt.Errorf("putting couchdb document: %w", err)
+		}
+
+		if resp.StatusCode == http.StatusConflict {
+			resp.Body.Close()
+			lastErr = fmt.Errorf("couchdb conflict for document %q", docID)
+			continue
+		}
+
+		respBody, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+			return fmt.Errorf("couchdb returned status %d for document %q", resp.StatusCode, docID)
+		}
+		if err != nil {
+			return fmt.Errorf("reading couchdb response: %w", err)
+		}
+
+		var putResp couchPutResponse
+		if err := jsoncodec.Unmarshal(respBody, &putResp); err != nil {
+			return fmt.Errorf("decoding couchdb response: %w", err)
+		}
+
+		result.Rev = putResp.Rev
+		return nil
+	}
+
+	return fmt.Errorf("giving up after %d couchdb conflicts: %w", couchDocMaxAttempts, lastErr)
+}
+
+// docID derives a document ID from the result timestamp plus a short hash,
+// varying with attempt so retries after a 409 don't collide again.
+func (s CouchDBSink) docID(result Result, attempt int) string {
+	h := sha256.Sum256([]byte(fmt.Sprintf("%s-%d-%v-%d", result.Timestamp, result.Sum, result.SumFloat, attempt)))
+	ts := strings.NewReplacer(":", "", ".", "").Replace(result.Timestamp)
+	return fmt.Sprintf("%s-%x", ts, h[:4])
+}
+
+// CouchDBSinkFromEnv builds a CouchDBSink from COUCHDB_URL, COUCHDB_DB and
+// the optional COUCHDB_USERNAME/COUCHDB_PASSWORD basic auth pair.
+func CouchDBSinkFromEnv() CouchDBSink {
+	return CouchDBSink{
+		URL:      os.Getenv("COUCHDB_URL"),
+		DB:       os.Getenv("COUCHDB_DB"),
+		Username: os.Getenv("COUCHDB_USERNAME"),
+		Password: os.Getenv("COUCHDB_PASSWORD"),
+		Client:   &http.Client{Timeout: 10 * time.Second},
+	}
+}
@@ -0,0 +1,144 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+)
+
+func TestFileSinkPutWritesIndentedJSON(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "output.json")
+	result := &Result{Timestamp: "2026-07-29T00:00:00Z", Sum: 42, Average: 1.5, Message: "ok"}
+
+	if err := (FileSink{Path: path}).Put(context.Background(), result); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("reading output file: %v", err)
+	}
+
+	var got Result
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("output file is not valid JSON: %v", err)
+	}
+	if got != *result {
+		t.Fatalf("round-tripped result = %+v, want %+v", got, *result)
+	}
+}
+
+func TestCouchDBSinkPutSetsRev(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"ok":true,"id":"whatever","rev":"1-abc"}`)
+	}))
+	defer server.Close()
+
+	sink := CouchDBSink{URL: server.URL, DB: "results", Client: server.Client()}
+	result := &Result{Timestamp: "2026-07-29T00:00:00Z", Sum: 42, Average: 1.5}
+
+	if err := sink.Put(context.Background(), result); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+	if result.Rev != "1-abc" {
+		t.Fatalf("result.Rev = %q, want %q", result.Rev, "1-abc")
+	}
+}
+
+func TestCouchDBSinkPutRetriesWithFreshIDOn409(t *testing.T) {
+	var requestIDs []string
+	var attempts int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestIDs = append(requestIDs, r.URL.Path)
+		if atomic.AddInt32(&attempts, 1) < 3 {
+			w.WriteHeader(http.StatusConflict)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"ok":true,"id":"whatever","rev":"1-abc"}`)
+	}))
+	defer server.Close()
+
+	sink := CouchDBSink{URL: server.URL, DB: "results", Client: server.Client()}
+	result := &Result{Timestamp: "2026-07-29T00:00:00Z", Sum: 42}
+
+	if err := sink.Put(context.Background(), result); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+	if got := atomic.LoadInt32(&attempts); got != 3 {
+		t.Fatalf("attempts = %d, want 3", got)
+	}
+	if requestIDs[0] == requestIDs[1] || requestIDs[1] == requestIDs[2] {
+		t.Fatalf("expected a fresh document ID per retry, got %v", requestIDs)
+	}
+}
+
+func TestCouchDBSinkPutDoesNotRetryOnNon409Error(t *testing.T) {
+	var attempts int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&attempts, 1)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	sink := CouchDBSink{URL: server.URL, DB: "results", Client: server.Client()}
+	result := &Result{Timestamp: "2026-07-29T00:00:00Z", Sum: 42}
+
+	if err := sink.Put(context.Background(), result); err == nil {
+		t.Fatal("expected an error for a 500 response")
+	}
+	if got := atomic.LoadInt32(&attempts); got != 1 {
+		t.Fatalf("attempts = %d, want 1 (non-409 errors must not retry)", got)
+	}
+}
+
+func TestCouchDBSinkPutGivesUpAfterMaxConflicts(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusConflict)
+	}))
+	defer server.Close()
+
+	sink := CouchDBSink{URL: server.URL, DB: "results", Client: server.Client()}
+	result := &Result{Timestamp: "2026-07-29T00:00:00Z", Sum: 42}
+
+	err := sink.Put(context.Background(), result)
+	if err == nil {
+		t.Fatal("expected an error after exhausting conflict retries")
+	}
+}
+
+// failingSink always errors, to verify multi-sink fan-out in Run isn't
+// short-circuited by one sink's failure.
+type failingSink struct{}
+
+func (failingSink) Put(ctx context.Context, result *Result) error {
+	return errors.New("sink unavailable")
+}
+
+func TestRunContinuesOtherSinksWhenOneFails(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "output.json")
+
+	_, err := Run(
+		WithOutputPath(path),
+		WithWriter(io.Discard),
+		WithSink(failingSink{}),
+		WithSink(FileSink{Path: path}),
+	)
+	if err == nil {
+		t.Fatal("expected Run to report the failing sink's error")
+	}
+
+	if _, statErr := os.Stat(path); statErr != nil {
+		t.Fatalf("FileSink should still have written its output despite the other sink failing: %v", statErr)
+	}
+}
@@ -0,0 +1,179 @@
+package agent
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Softbank-Final/NanoGrid-Agent-PY/examples/go/jsoncodec"
+)
+
+// Meta carries the provider-style metadata that accompanies a time series
+// response.
+type Meta struct {
+	Information   string `json:"1. Information"`
+	Symbol        string `json:"2. Symbol"`
+	LastRefreshed string `json:"3. Last Refreshed"`
+	Interval      string `json:"4. Interval"`
+	OutputSize    string `json:"5. Output Size"`
+	TimeZone      string `json:"6. Time Zone"`
+}
+
+// Point is one time series entry. The upstream provider encodes these
+// numbers as JSON strings (e.g. "98.3050"), so Point implements its own
+// UnmarshalJSON to parse them into proper Go numbers.
+type Point struct {
+	Open   float64
+	High   float64
+	Low    float64
+	Close  float64
+	Volume int64
+}
+
+// UnmarshalJSON decodes a provider-style point whose numeric fields are
+// JSON strings, e.g. {"1. open": "98.3050", "5. volume": "1234"}.
+func (p *Point) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		Open   string `json:"1. open"`
+		High   string `json:"2. high"`
+		Low    string `json:"3. low"`
+		Close  string `json:"4. close"`
+		Volume string `json:"5. volume"`
+	}
+	if err := jsoncodec.Unmarshal(data, &raw); err != nil {
+		return fmt.Errorf("decoding point: %w", err)
+	}
+
+	var err error
+	if p.Open, err = strconv.ParseFloat(raw.Open, 64); err != nil {
+		return fmt.Errorf("parsing open %q: %w", raw.Open, err)
+	}
+	if p.High, err = strconv.ParseFloat(raw.High, 64); err != nil {
+		return fmt.Errorf("parsing high %q: %w", raw.High, err)
+	}
+	if p.Low, err = strconv.ParseFloat(raw.Low, 64); err != nil {
+		return fmt.Errorf("parsing low %q: %w", raw.Low, err)
+	}
+	if p.Close, err = strconv.ParseFloat(raw.Close, 64); err != nil {
+		return fmt.Errorf("parsing close %q: %w", raw.Close, err)
+	}
+	if p.Volume, err = strconv.ParseInt(raw.Volume, 10, 64); err != nil {
+		return fmt.Errorf("parsing volume %q: %w", raw.Volume, err)
+	}
+	return nil
+}
+
+// Field returns the named numeric field ("open", "high", "low", "close" or
+// "volume"), case-insensitively.
+func (p Point) Field(name string) (float64, error) {
+	switch strings.ToLower(name) {
+	case "open":
+		return p.Open, nil
+	case "high":
+		return p.High, nil
+	case "low":
+		return p.Low, nil
+	case "close":
+		return p.Close, nil
+	case "volume":
+		return float64(p.Volume), nil
+	default:
+		return 0, fmt.Errorf("unknown field %q", name)
+	}
+}
+
+// Envelope is the top-level shape of a time series response: metadata plus
+// a map of timestamp to Point.
+type Envelope struct {
+	Meta   Meta             `json:"Meta Data"`
+	Series map[string]Point `json:"Time Series (Daily)"`
+}
+
+const (
+	fetchMaxAttempts    = 3
+	fetchAttemptTimeout = 10 * time.Second
+	fetchInitialBackoff = 200 * time.Millisecond
+)
+
+// fetchEnvelope GETs url and decodes the response as an Envelope, making up
+// to fetchMaxAttempts attempts in all with exponential backoff between them;
+// a failed attempt (request, status or decode error) is retried while
+// attempts remain. Each attempt is bounded by its own timeout derived from
+// ctx.
+func fetchEnvelope(ctx context.Context, client *http.Client, url string) (Envelope, error) {
+	var lastErr error
+	backoff := fetchInitialBackoff
+
+	for attempt := 0; attempt < fetchMaxAttempts; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-ctx.Done():
+				return Envelope{}, ctx.Err()
+			case <-time.After(backoff):
+			}
+			backoff *= 2
+		}
+
+		env, err := doFetch(ctx, client, url)
+		if err == nil {
+			return env, nil
+		}
+		lastErr = err
+	}
+
+	return Envelope{}, fmt.Errorf("fetching %s after %d attempts: %w", url, fetchMaxAttempts, lastErr)
+}
+
+// computeFromHTTPSource fetches the Envelope at url and sums the named
+// field across all of its Points.
+func computeFromHTTPSource(ctx context.Context, client *http.Client, url, field string) (count int64, sum float64, err error) {
+	env, err := fetchEnvelope(ctx, client, url)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	for _, point := range env.Series {
+		v, err := point.Field(field)
+		if err != nil {
+			return 0, 0, err
+		}
+		sum += v
+		count++
+	}
+	return count, sum, nil
+}
+
+func doFetch(ctx context.Context, client *http.Client, url string) (Envelope, error) {
+	reqCtx, cancel := context.WithTimeout(ctx, fetchAttemptTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(reqCtx, http.MethodGet, url, nil)
+	if err != nil {
+		return Envelope{}, fmt.Errorf("building request: %w", err)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return Envelope{}, fmt.Errorf("requesting %s: %w", url, err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return Envelope{}, fmt.Errorf("reading response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return Envelope{}, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+
+	var env Envelope
+	if err := jsoncodec.Unmarshal(body, &env); err != nil {
+		return Envelope{}, fmt.Errorf("decoding envelope: %w", err)
+	}
+	return env, nil
+}
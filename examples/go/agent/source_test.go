@@ -0,0 +1,125 @@
+package agent
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+)
+
+const fixtureBody = `{
+  "Meta Data": {
+    "1. Information": "Daily Prices",
+    "2. Symbol": "ACME",
+    "3. Last Refreshed": "2026-07-28",
+    "4. Interval": "daily",
+    "5. Output Size": "Compact",
+    "6. Time Zone": "US/Eastern"
+  },
+  "Time Series (Daily)": {
+    "2026-07-28": {
+      "1. open": "98.3050",
+      "2. high": "99.1200",
+      "3. low": "97.5000",
+      "4. close": "98.9900",
+      "5. volume": "1234567"
+    },
+    "2026-07-27": {
+      "1. open": "96.0000",
+      "2. high": "97.0000",
+      "3. low": "95.5000",
+      "4. close": "96.5000",
+      "5. volume": "2000000"
+    }
+  }
+}`
+
+func TestDoFetchDecodesProviderShape(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(fixtureBody))
+	}))
+	defer server.Close()
+
+	env, err := doFetch(context.Background(), server.Client(), server.URL)
+	if err != nil {
+		t.Fatalf("doFetch: %v", err)
+	}
+
+	if env.Meta.Symbol != "ACME" {
+		t.Fatalf("Meta.Symbol = %q, want ACME", env.Meta.Symbol)
+	}
+	if len(env.Series) != 2 {
+		t.Fatalf("len(Series) = %d, want 2", len(env.Series))
+	}
+
+	point, ok := env.Series["2026-07-28"]
+	if !ok {
+		t.Fatalf("missing point for 2026-07-28")
+	}
+	if point.Close != 98.99 || point.Volume != 1234567 {
+		t.Fatalf("point = %+v, want Close=98.99 Volume=1234567", point)
+	}
+}
+
+func TestFetchEnvelopeRetriesOnFailure(t *testing.T) {
+	var attempts int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&attempts, 1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(fixtureBody))
+	}))
+	defer server.Close()
+
+	env, err := fetchEnvelope(context.Background(), server.Client(), server.URL)
+	if err != nil {
+		t.Fatalf("fetchEnvelope: %v", err)
+	}
+	if got := atomic.LoadInt32(&attempts); got != 3 {
+		t.Fatalf("attempts = %d, want 3", got)
+	}
+	if len(env.Series) != 2 {
+		t.Fatalf("len(Series) = %d, want 2", len(env.Series))
+	}
+}
+
+func TestFetchEnvelopeGivesUpAfterMaxAttempts(t *testing.T) {
+	var attempts int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&attempts, 1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	_, err := fetchEnvelope(context.Background(), server.Client(), server.URL)
+	if err == nil {
+		t.Fatal("expected an error after exhausting retries")
+	}
+	if got := atomic.LoadInt32(&attempts); got != fetchMaxAttempts {
+		t.Fatalf("attempts = %d, want %d", got, fetchMaxAttempts)
+	}
+}
+
+func TestComputeFromHTTPSourceSumsField(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(fixtureBody))
+	}))
+	defer server.Close()
+
+	count, sum, err := computeFromHTTPSource(context.Background(), server.Client(), server.URL, "close")
+	if err != nil {
+		t.Fatalf("computeFromHTTPSource: %v", err)
+	}
+	if count != 2 {
+		t.Fatalf("count = %d, want 2", count)
+	}
+	const want = 98.99 + 96.5
+	if diff := sum - want; diff > 1e-9 || diff < -1e-9 {
+		t.Fatalf("sum = %v, want %v", sum, want)
+	}
+}
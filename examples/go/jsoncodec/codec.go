@@ -0,0 +1,25 @@
+//go:build !jsoniter
+
+// Package jsoncodec centralizes JSON marshaling for the agent so the
+// backend can be swapped with a build tag without touching call sites.
+// This file is the default backend, backed by encoding/json; build with
+// -tags jsoniter to switch to codec_jsoniter.go instead.
+package jsoncodec
+
+import "encoding/json"
+
+// Marshal serializes v using the standard library encoder.
+func Marshal(v any) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+// MarshalIndent serializes v using the standard library encoder with the
+// given prefix and indent.
+func MarshalIndent(v any, prefix, indent string) ([]byte, error) {
+	return json.MarshalIndent(v, prefix, indent)
+}
+
+// Unmarshal decodes data into v using the standard library decoder.
+func Unmarshal(data []byte, v any) error {
+	return json.Unmarshal(data, v)
+}
@@ -0,0 +1,63 @@
+package jsoncodec_test
+
+import (
+	"testing"
+
+	"github.com/Softbank-Final/NanoGrid-Agent-PY/examples/go/jsoncodec"
+)
+
+// resultLike mirrors agent.Result's shape so the benchmark does not depend
+// on the agent package.
+type resultLike struct {
+	Timestamp string  `json:"timestamp"`
+	GoVersion string  `json:"goVersion"`
+	Sum       int64   `json:"sum,omitempty"`
+	SumFloat  float64 `json:"sumFloat,omitempty"`
+	Average   float64 `json:"average"`
+	Message   string  `json:"message"`
+	Rev       string  `json:"rev,omitempty"`
+}
+
+type entry struct {
+	ID    int     `json:"id"`
+	Name  string  `json:"name"`
+	Value float64 `json:"value"`
+}
+
+func sampleResult() resultLike {
+	return resultLike{
+		Timestamp: "2026-07-29T00:00:00Z",
+		GoVersion: "go1.21.6",
+		Sum:       500500,
+		Average:   500.5,
+		Message:   "Function executed successfully!",
+	}
+}
+
+func sampleEntries(n int) []entry {
+	entries := make([]entry, n)
+	for i := range entries {
+		entries[i] = entry{ID: i, Name: "entry", Value: float64(i) * 1.5}
+	}
+	return entries
+}
+
+func BenchmarkMarshalResult(b *testing.B) {
+	result := sampleResult()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := jsoncodec.Marshal(result); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkMarshalEntries10k(b *testing.B) {
+	entries := sampleEntries(10_000)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := jsoncodec.Marshal(entries); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
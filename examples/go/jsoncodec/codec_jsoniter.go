@@ -0,0 +1,27 @@
+//go:build jsoniter
+
+// This file is the jsoniter-backed implementation of jsoncodec, built with
+// -tags jsoniter for throughput-sensitive deployments. See codec.go for the
+// default stdlib backend and package docs.
+package jsoncodec
+
+import jsoniter "github.com/json-iterator/go"
+
+var json = jsoniter.ConfigCompatibleWithStandardLibrary
+
+// Marshal serializes v using jsoniter configured to match encoding/json.
+func Marshal(v any) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+// MarshalIndent serializes v using jsoniter configured to match
+// encoding/json.
+func MarshalIndent(v any, prefix, indent string) ([]byte, error) {
+	return json.MarshalIndent(v, prefix, indent)
+}
+
+// Unmarshal decodes data into v using jsoniter configured to match
+// encoding/json.
+func Unmarshal(data []byte, v any) error {
+	return json.Unmarshal(data, v)
+}
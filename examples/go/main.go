@@ -1,69 +1,55 @@
 package main
 
 import (
-	"encoding/json"
+	"flag"
 	"fmt"
 	"os"
-	"runtime"
-	"time"
-)
+	"strings"
 
-type Result struct {
-	Timestamp  string  `json:"timestamp"`
-	GoVersion  string  `json:"goVersion"`
-	Sum        int     `json:"sum"`
-	Average    float64 `json:"average"`
-	Message    string  `json:"message"`
-}
+	"github.com/Softbank-Final/NanoGrid-Agent-PY/examples/go/agent"
+)
 
 func main() {
-	fmt.Println("=== NanoGrid Go Function ===")
-	fmt.Println("Starting execution...")
-
-	// 환경 정보 출력
-	fmt.Printf("Go version: %s\n", runtime.Version())
-	fmt.Printf("OS/Arch: %s/%s\n", runtime.GOOS, runtime.GOARCH)
-	wd, _ := os.Getwd()
-	fmt.Printf("Working directory: %s\n", wd)
-
-	// 간단한 계산
-	numbers := make([]int, 1000)
-	for i := 0; i < 1000; i++ {
-		numbers[i] = i + 1
+	start := flag.Int("start", 1, "start of the integer range to sum (inclusive)")
+	end := flag.Int("end", 1000, "end of the integer range to sum (inclusive)")
+	outputPath := flag.String("output", "output.json", "path to write the result JSON to")
+	sinkList := flag.String("sink", "file", "comma-separated result sinks to use: file,couchdb")
+	workers := flag.Int("workers", 0, "number of compute workers (0 = runtime.GOMAXPROCS(0))")
+	partitionFactor := flag.Int("partition-factor", 0, "partitions created per worker (0 = agent default)")
+	source := flag.String("source", "", "URL of a time series JSON endpoint to sum instead of the synthetic range")
+	field := flag.String("field", "close", "time series field to sum when --source is set: open,high,low,close,volume")
+	flag.Parse()
+
+	opts := []agent.Option{
+		agent.WithRange(*start, *end),
+		agent.WithOutputPath(*outputPath),
 	}
-
-	sum := 0
-	for _, num := range numbers {
-		sum += num
+	if *workers > 0 {
+		opts = append(opts, agent.WithWorkers(*workers))
 	}
-	average := float64(sum) / float64(len(numbers))
-
-	fmt.Println("\nCalculation results:")
-	fmt.Printf("Sum: %d\n", sum)
-	fmt.Printf("Average: %.2f\n", average)
-
-	// 결과를 JSON 파일로 저장
-	result := Result{
-		Timestamp: time.Now().Format(time.RFC3339),
-		GoVersion: runtime.Version(),
-		Sum:       sum,
-		Average:   average,
-		Message:   "Function executed successfully!",
+	if *partitionFactor > 0 {
+		opts = append(opts, agent.WithPartitionFactor(*partitionFactor))
+	}
+	if *source != "" {
+		opts = append(opts, agent.WithHTTPSource(*source, *field))
 	}
 
-	data, err := json.MarshalIndent(result, "", "  ")
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error marshaling JSON: %v\n", err)
-		os.Exit(1)
+	for _, sink := range strings.Split(*sinkList, ",") {
+		switch strings.TrimSpace(sink) {
+		case "file":
+			opts = append(opts, agent.WithSink(agent.FileSink{Path: *outputPath}))
+		case "couchdb":
+			opts = append(opts, agent.WithSink(agent.CouchDBSinkFromEnv()))
+		case "":
+		default:
+			fmt.Fprintf(os.Stderr, "Error: unknown sink %q\n", sink)
+			os.Exit(1)
+		}
 	}
 
-	err = os.WriteFile("output.json", data, 0644)
+	_, err := agent.Run(opts...)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error writing file: %v\n", err)
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		os.Exit(1)
 	}
-
-	fmt.Println("\n✓ Output written to output.json")
-	fmt.Println("\nExecution completed successfully!")
 }
-